@@ -0,0 +1,202 @@
+package fuzzy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func corpus(n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("word%d", i%500)
+	}
+	return words
+}
+
+// TestConcurrentTrainAndQuery exercises TrainWord, Suggestions and the
+// SetDepth/SetThreshold setters from multiple goroutines at once under
+// -race; SetAlphabet is covered by TestEdits1ConcurrentSetAlphabet.
+func TestConcurrentTrainAndQuery(t *testing.T) {
+	model := NewModel()
+	words := corpus(2000)
+
+	var wg sync.WaitGroup
+	wg.Add(4)
+
+	go func() {
+		defer wg.Done()
+		for _, w := range words {
+			model.TrainWord(w)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			model.Suggestions("wrd1", false)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			model.SetDepth(2)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			model.SetThreshold(4)
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestTrainConcurrentMatchesSequential checks that routing terms to
+// workers by hash, rather than splitting a single term's occurrences
+// across workers, reproduces the same term counts as sequential Train
+// with 8 workers.
+func TestTrainConcurrentMatchesSequential(t *testing.T) {
+	words := corpus(3000)
+
+	sequential := NewModel()
+	sequential.Train(words)
+
+	concurrent := NewModel()
+	ch := make(chan string)
+	go func() {
+		for _, w := range words {
+			ch <- w
+		}
+		close(ch)
+	}()
+	concurrent.TrainConcurrent(ch, 8)
+
+	for term, count := range sequential.Data {
+		if concurrent.Data[term] != count {
+			t.Fatalf("Data[%q] = %d, want %d", term, concurrent.Data[term], count)
+		}
+	}
+	if len(concurrent.Data) != len(sequential.Data) {
+		t.Fatalf("len(Data) = %d, want %d", len(concurrent.Data), len(sequential.Data))
+	}
+}
+
+// TestTrainConcurrentSeedsPreExistingCounts checks that a term whose
+// pre-existing count plus new TrainConcurrent occurrences crosses
+// threshold gets the same suggest entries as sequential Train would,
+// rather than each worker's fresh local model missing the exact
+// equality check in TrainWord.
+func TestTrainConcurrentSeedsPreExistingCounts(t *testing.T) {
+	seq := NewModel()
+	seq.SetThreshold(4)
+	seq.Train([]string{"cat", "cat", "cat", "cat"})
+
+	conc := NewModel()
+	conc.SetThreshold(4)
+	conc.Train([]string{"cat", "cat", "cat"})
+
+	ch := make(chan string, 1)
+	ch <- "cat"
+	close(ch)
+	conc.TrainConcurrent(ch, 4)
+
+	if conc.Data["cat"] != seq.Data["cat"] {
+		t.Fatalf("Data[cat] = %d, want %d", conc.Data["cat"], seq.Data["cat"])
+	}
+	for key, terms := range seq.suggest {
+		found := false
+		for _, term := range conc.suggest[key] {
+			if term == "cat" && contains(terms, "cat") {
+				found = true
+			}
+		}
+		if contains(terms, "cat") && !found {
+			t.Fatalf("suggest[%q] = %v, want to contain %q as in sequential", key, conc.suggest[key], "cat")
+		}
+	}
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// TestEdits1ConcurrentSetAlphabet exercises Edits1 against SetAlphabet
+// from another goroutine under -race: Edits1 must read model.alphabet
+// under its own lock rather than racing with a concurrent write.
+func TestEdits1ConcurrentSetAlphabet(t *testing.T) {
+	model := NewModel()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			model.Edits1("hello")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			model.SetAlphabet([]rune("abc"))
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestTrainReaderConcurrentSetPreprocessors exercises TrainReader
+// against SetPreprocessors from another goroutine under -race:
+// model.preprocessors must be guarded by model.mu on both the read and
+// write side.
+func TestTrainReaderConcurrentSetPreprocessors(t *testing.T) {
+	model := NewModel()
+	text := strings.Repeat("the quick brown fox ", 200)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			model.TrainReader(strings.NewReader(text), nil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			model.SetPreprocessors(strings.ToLower)
+		}
+	}()
+
+	wg.Wait()
+}
+
+func BenchmarkTrainSequential(b *testing.B) {
+	words := corpus(5000)
+	for i := 0; i < b.N; i++ {
+		NewModel().Train(words)
+	}
+}
+
+func BenchmarkTrainConcurrent(b *testing.B) {
+	words := corpus(5000)
+	for i := 0; i < b.N; i++ {
+		model := NewModel()
+		ch := make(chan string)
+		go func() {
+			for _, w := range words {
+				ch <- w
+			}
+			close(ch)
+		}()
+		model.TrainConcurrent(ch, 4)
+	}
+}
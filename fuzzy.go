@@ -3,20 +3,17 @@ package fuzzy
 import(
 	"fmt"
 	"os"
-	"bufio"
+	"hash/fnv"
+	"io"
 	"strings"
+	"sync"
 )
 
-type Pair struct {
-	str1 string
-	str2 string
-}
-
 type Potential struct {
-	term  		string
-	score 		int 
-	leven 		int
-	method 		int 	// 0 - is word, 1 - suggest maps to input, 2 - input delete maps to dictionary, 3 - input delete maps to suggest 
+	Term  		string
+	Score 		int
+	Leven 		int
+	Method 		int 	// 0 - is word, 1 - suggest maps to input, 2 - input delete maps to dictionary, 3 - input delete maps to suggest, 4 - found by Lookup
 }
 
 type Model struct {
@@ -26,6 +23,9 @@ type Model struct {
 	depth		int
 	threshold 	int
 	chars 		int
+	alphabet	[]rune
+	preprocessors	[]func(string) string
+	mu		sync.RWMutex // guards Data, maxcount, suggest, depth, threshold, alphabet and preprocessors
 }
 
 func NewModel() *Model {
@@ -44,15 +44,29 @@ func (model *Model) Init() *Model {
 // Change the default depth value of the model. This sets how many
 // character differences are indexed. The default is 2.
 func (model *Model) SetDepth(val int) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
 	model.depth = val
 }
 
 // Change the default threshold of the model. This is how many times
 // a term must be seen before suggestions are created for it
 func (model *Model) SetThreshold(val int) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
 	model.threshold = val
 }
 
+// SetPreprocessors configures token normalization for TrainReader: each
+// token is passed through fns in order before being trained. A
+// preprocessor that returns "" drops the token, which is how stopword
+// filtering or stemming hooks are expected to work.
+func (model *Model) SetPreprocessors(fns ...func(string) string) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+	model.preprocessors = fns
+}
+
 func min(a, b int) int {
   if a < b {
     return a
@@ -67,34 +81,56 @@ func max(a, b int) int {
   return a
 }
 
+// Levenshtein returns the Levenshtein edit distance between a and b,
+// counting insertions, deletions and substitutions. Inputs are compared
+// rune by rune so multi-byte UTF-8 characters are treated as single
+// edits rather than being split across bytes.
 func Levenshtein(a, b string) int {
+  return levenshtein([]rune(a), []rune(b), false)
+}
+
+// DamerauLevenshtein returns the Damerau-Levenshtein edit distance
+// between a and b: like Levenshtein, but an adjacent transposition
+// (e.g. "ab" -> "ba") also costs 1 instead of 2.
+func DamerauLevenshtein(a, b string) int {
+  return levenshtein([]rune(a), []rune(b), true)
+}
+
+// levenshtein computes the edit distance between two rune slices using
+// three rolling rows (the oldest is only read in damerau mode) sized to the shorter input
+// rather than the longer one, so memory scales with min(len(a), len(b)).
+func levenshtein(a, b []rune, damerau bool) int {
   n, m := len(a), len(b)
   if n > m {
     a, b = b, a
     n, m = m, n
   }
 
-  current  := make([]int, m+1)
-  previous := make([]int, m+1)
-  var i, j, add, delete, change int
+  previous2 := make([]int, n+1)
+  previous  := make([]int, n+1)
+  current   := make([]int, n+1)
+  for j := 0; j <= n; j++ { previous[j] = j }
 
-  for i = 1; i <= m; i++ {
-    copy(previous, current)
-    for j = 0; j <= m; j++ { current[j] = 0 }
+  for i := 1; i <= m; i++ {
     current[0] = i
-    for j = 1; j <= n; j++ {
+    for j := 1; j <= n; j++ {
+      cost := 1
       if a[j-1] == b[i-1] {
-        current[j] = previous[j-1]
-      } else {
-        add    = previous[j] + 1
-        delete = current[j-1] + 1
-        change = previous[j-1] + 1
-        current[j] = min(min(add, delete), change)
+        cost = 0
       }
+      add    := previous[j] + 1
+      delete := current[j-1] + 1
+      change := previous[j-1] + cost
+      best   := min(min(add, delete), change)
+      if damerau && i > 1 && j > 1 && a[j-1] == b[i-2] && a[j-2] == b[i-1] {
+        best = min(best, previous2[j-2]+1)
+      }
+      current[j] = best
     }
+    previous2, previous, current = previous, current, previous2
   }
 
-  return current[n]
+  return previous[n]
 }
 
 // Add an array of words to train the model in bulk
@@ -106,6 +142,9 @@ func (model *Model) Train(terms []string) {
 
 // Train the model word by word
 func (model *Model) TrainWord(term string) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
 	model.Data[term]++
 	// Set the max
 	if model.Data[term] > model.maxcount {
@@ -130,16 +169,144 @@ func (model *Model) TrainWord(term string) {
 	}
 }
 
-// Edits at any depth for a given term. The depth of the model is used
+// TrainReader trains the model from tok, a Tokenizer over r, without
+// loading the whole corpus into memory. If tok is nil, r is tokenized
+// with NewWordTokenizer. Each token is lower-cased and passed through
+// any preprocessors set with SetPreprocessors before being trained; a
+// preprocessor returning "" drops the token. If tok also implements
+// Err() error, that error is returned once the stream is exhausted.
+func (model *Model) TrainReader(r io.Reader, tok Tokenizer) error {
+	if tok == nil {
+		tok = NewWordTokenizer(r)
+	}
+
+	model.mu.RLock()
+	preprocessors := model.preprocessors
+	model.mu.RUnlock()
+
+	for {
+		term, ok := tok.Next()
+		if !ok {
+			break
+		}
+		term = strings.ToLower(term)
+		for _, preprocess := range preprocessors {
+			term = preprocess(term)
+			if term == "" {
+				break
+			}
+		}
+		if term == "" {
+			continue
+		}
+		model.TrainWord(term)
+	}
+	if errer, ok := tok.(interface{ Err() error }); ok {
+		return errer.Err()
+	}
+	return nil
+}
+
+// TrainConcurrent drains terms from a channel, training workers
+// independent local models in parallel and merging them into model once
+// terms is closed and every worker has finished. Each term is routed by
+// an FNV hash to the same worker on every call, so all occurrences of a
+// given term land in one local model and its frequency threshold
+// behaves exactly as it would under sequential Train, even when model
+// already has pre-existing counts for that term: each local model is
+// first seeded with model's current count for every term it owns, so a
+// term whose pre-existing count plus new occurrences crosses threshold
+// still gets its suggest entries populated. The seeded baseline is
+// subtracted back out before merging, so model.Merge only adds the new
+// occurrences rather than double-counting. The only serialized work is
+// the final Merge per worker, not per term, which is what lets this
+// actually run faster than TrainWord on a single shared map under a
+// single lock.
+func (model *Model) TrainConcurrent(terms <-chan string, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	model.mu.RLock()
+	depth, threshold, alphabet := model.depth, model.threshold, model.alphabet
+	existing := make(map[string]int, len(model.Data))
+	for term, count := range model.Data {
+		existing[term] = count
+	}
+	model.mu.RUnlock()
+
+	hasher := fnv.New32a()
+	workerFor := func(term string) uint32 {
+		hasher.Reset()
+		hasher.Write([]byte(term))
+		return hasher.Sum32() % uint32(workers)
+	}
+
+	locals := make([]*Model, workers)
+	seeds := make([]map[string]int, workers)
+	queues := make([]chan string, workers)
+	for i := 0; i < workers; i++ {
+		local := NewModel()
+		local.SetDepth(depth)
+		local.SetThreshold(threshold)
+		local.SetAlphabet(alphabet)
+		locals[i] = local
+		seeds[i] = make(map[string]int)
+		queues[i] = make(chan string, 64)
+	}
+
+	for term, count := range existing {
+		w := workerFor(term)
+		locals[w].Data[term] = count
+		seeds[w][term] = count
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(local *Model, queue <-chan string) {
+			defer wg.Done()
+			for term := range queue {
+				local.TrainWord(term)
+			}
+		}(locals[i], queues[i])
+	}
+
+	for term := range terms {
+		queues[workerFor(term)] <- term
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+
+	for i, local := range locals {
+		for term, baseline := range seeds[i] {
+			local.Data[term] -= baseline
+			if local.Data[term] == 0 {
+				delete(local.Data, term)
+			}
+		}
+		model.Merge(local)
+	}
+}
+
+// Edits at any depth for a given term. The depth argument is used, not
+// the depth of the model.
+// This always uses delete-only edits, regardless of whether an
+// alphabet has been set with SetAlphabet: it backs the symmetric-delete
+// index in model.suggest and the Lookup query path, both of which rely
+// on deletes alone, not the fuller insert/replace/transpose edit set
+// that (*Model).Edits1 exposes directly.
 func (model *Model) EditsMulti(term string, depth int) []string {
-	edits := Edits1(term)
+	edits := edits1Deletes(term)
 	for {
 		depth--
 		if depth == 0 {
 			break
 		}
 		for _, edit := range edits {
-			edits2 := Edits1(edit)
+			edits2 := edits1Deletes(edit)
 			for _, edit2 := range edits2 {
 				edits = append(edits, edit2)
 			}
@@ -148,28 +315,102 @@ func (model *Model) EditsMulti(term string, depth int) []string {
 	return edits
 }
 
-// Edits1 creates a set of terms that are 1 char delete from the input term
+// SetAlphabet configures the set of runes Edits1 uses to generate
+// inserts, replacements and transpositions. Until it is called, Edits1
+// only emits deletes, which is all the symmetric-delete index in
+// model.suggest needs.
+func (model *Model) SetAlphabet(alphabet []rune) {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+	model.alphabet = alphabet
+}
+
+// Edits1 is a deprecated free-function wrapper around (*Model).Edits1 on
+// a model with no alphabet set, kept for callers of the pre-rune-correct
+// API. It only produces deletes, rune by rune; use (*Model).Edits1 with
+// SetAlphabet for inserts, replacements and transpositions.
+//
+// Deprecated: use (*Model).Edits1 instead.
 func Edits1(word string) []string {
+	return new(Model).Edits1(word)
+}
 
-  splits := []Pair{}
-  for i := 0; i <= len(word); i++ {
-    splits = append(splits, Pair{word[:i], word[i:]})
+// edits1Deletes returns every string formed by deleting a single rune
+// from word. This is the delete-only half of Edits1, kept independent
+// of model.alphabet so the symmetric-delete index built by EditsMulti
+// never grows the alphabet-gated inserts/replacements/transpositions
+// that (*Model).Edits1 produces once SetAlphabet has been called.
+func edits1Deletes(word string) []string {
+  runes := []rune(word)
+  n := len(runes)
+
+  deletes := make([]string, 0, n)
+  for i := 0; i < n; i++ {
+    deletes = append(deletes, string(runes[:i])+string(runes[i+1:]))
   }
+  return deletes
+}
 
-  total_set := []string{}
-  for _, elem := range splits {
+// Edits1 creates the set of terms that are a single edit away from word.
+// Deletes are always produced, rune by rune so multi-byte characters
+// are treated as whole characters rather than split across bytes. If
+// an alphabet has been set with SetAlphabet, inserts, replacements and
+// adjacent transpositions are produced as well, using that alphabet.
+func (model *Model) Edits1(word string) []string {
 
-	//deletion
-	if len(elem.str2) > 0 {
-		total_set = append(total_set, elem.str1+elem.str2[1:])
-	} else {
-		total_set = append(total_set, elem.str1)
-	}
+  model.mu.RLock()
+  alphabet := model.alphabet
+  model.mu.RUnlock()
+
+  runes := []rune(word)
+  n := len(runes)
+
+  total_set := edits1Deletes(word)
+
+  if len(alphabet) == 0 {
+    return total_set
+  }
+
+  // insertion
+  for i := 0; i <= n; i++ {
+    for _, r := range alphabet {
+      candidate := make([]rune, 0, n+1)
+      candidate = append(candidate, runes[:i]...)
+      candidate = append(candidate, r)
+      candidate = append(candidate, runes[i:]...)
+      total_set = append(total_set, string(candidate))
+    }
+  }
+
+  // replacement
+  for i := 0; i < n; i++ {
+    for _, r := range alphabet {
+      if r == runes[i] {
+        continue
+      }
+      candidate := make([]rune, n)
+      copy(candidate, runes)
+      candidate[i] = r
+      total_set = append(total_set, string(candidate))
+    }
+  }
 
+  // transposition
+  for i := 0; i < n-1; i++ {
+    if runes[i] == runes[i+1] {
+      continue
+    }
+    candidate := make([]rune, n)
+    copy(candidate, runes)
+    candidate[i], candidate[i+1] = candidate[i+1], candidate[i]
+    total_set = append(total_set, string(candidate))
   }
+
   return total_set
 }
 
+// score reads model.Data directly and assumes the caller already holds
+// model.mu (read or write locked).
 func (model *Model) score(input string) int {
 	if score, ok := model.Data[input]; ok {
 		return score
@@ -183,18 +424,12 @@ func best(input string, potential map[string]*Potential) string {
 	bestcalc := 0
 	for i := 0; i < 4; i++ {
 		for _, pot := range potential {
-			if pot.leven == 0 {
-				return pot.term
-			} else if pot.leven == i {
-				if pot.score > bestcalc {
-					bestcalc = pot.score
-					// If the first letter is the same, that's a good sign. Bias these potentials
-					
-					if pot.term[0] == input[0] {
-						bestcalc += bestcalc * 100
-					}
-					
-					best = pot.term
+			if pot.Leven == 0 {
+				return pot.Term
+			} else if pot.Leven == i {
+				if pot.Score > bestcalc {
+					bestcalc = pot.Score
+					best = pot.Term
 				}
 			}
 		}
@@ -218,8 +453,11 @@ func (model *Model) CheckKnown(input string, correct string) bool {
 		return true
 	}
 	if pot, ok := suggestions[correct]; !ok {
-		if model.score(correct) > 0 {
-			fmt.Printf("\"%v\" - %v (%v) not in the suggestions. (%v) best option.\n", input, correct, model.score(correct), best)
+		model.mu.RLock()
+		correctScore := model.score(correct)
+		model.mu.RUnlock()
+		if correctScore > 0 {
+			fmt.Printf("\"%v\" - %v (%v) not in the suggestions. (%v) best option.\n", input, correct, correctScore, best)
 			for _, sugg := range suggestions {
 				fmt.Printf("	%v\n", sugg)
 			}
@@ -236,12 +474,15 @@ func (model *Model) CheckKnown(input string, correct string) bool {
 // For a given input term, suggest some alternatives. If exhaustive, each of the 4
 // cascading checks will be performed and all potentials will be sorted accordingly
 func (model *Model) suggestPotential(input string, exhaustive bool) map[string]*Potential {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
 	input = strings.ToLower(input)
 	suggestions := make(map[string]*Potential, 20)
 
 	// 0 - If this is a dictionary term we're all good, no need to go further
 	if model.score(input) > 5 {
-		suggestions[input] = &Potential{term : input, score : model.score(input), leven : 0, method : 0}
+		suggestions[input] = &Potential{Term : input, Score : model.score(input), Leven : 0, Method : 0}
 		if !exhaustive {
 			return suggestions
 		}
@@ -251,7 +492,7 @@ func (model *Model) suggestPotential(input string, exhaustive bool) map[string]*
 	if sugg, ok := model.suggest[input]; ok {
 		for _, pot := range sugg {
 			if _, ok := suggestions[pot]; !ok {
-				suggestions[pot] = &Potential{term : pot, score : model.score(pot), leven : Levenshtein(input, pot), method : 1}
+				suggestions[pot] = &Potential{Term : pot, Score : model.score(pot), Leven : Levenshtein(input, pot), Method : 1}
 			}
 		}
 
@@ -265,9 +506,9 @@ func (model *Model) suggestPotential(input string, exhaustive bool) map[string]*
 	edits := model.EditsMulti(input, model.depth)
 	for _, edit := range edits {
 		score := model.score(edit)
-		if score > 0 && len(edit) > 2 { 
+		if score > 0 && len(edit) > 2 {
 			if _, ok := suggestions[edit]; !ok {
-				suggestions[edit] = &Potential{term : edit, score : score, leven : Levenshtein(input, edit), method : 2}
+				suggestions[edit] = &Potential{Term : edit, Score : score, Leven : Levenshtein(input, edit), Method : 2}
 			}
 			if (score > max) {
 				max = score
@@ -291,7 +532,7 @@ func (model *Model) suggestPotential(input string, exhaustive bool) map[string]*
 				lev := Levenshtein(input, pot)
 				if lev <= model.depth + 1 { // The +1 doesn't seem to impact speed, but has greater coverage when the depth is not sufficient to make suggestions
 					if _, ok := suggestions[pot]; !ok {
-						suggestions[pot] = &Potential{term : pot, score : model.score(pot), leven : lev, method : 3}
+						suggestions[pot] = &Potential{Term : pot, Score : model.score(pot), Leven : lev, Method : 3}
 					}
 				}
 			}
@@ -300,40 +541,31 @@ func (model *Model) suggestPotential(input string, exhaustive bool) map[string]*
 	return suggestions
 }
 
-func (model *Model) Suggestions(input string, exhaustive bool) []string {
-	suggestions := model.suggestPotential(input, exhaustive)
-	output := make([]string, 10)
-	for _, suggestion := range suggestions {
-		output = append(output, suggestion.term)
-	}
-	return output
-}
-
 // Return the most likely correction for the input term
 func (model *Model) SpellCheck(input string) string {
 	suggestions := model.suggestPotential(input, false)
 	return best(input, suggestions)
 }
 
+// SampleEnglish is a thin demo helper that loads data/big.txt into
+// memory as lower-cased words. For larger corpora, use TrainReader
+// directly instead so the whole file doesn't have to fit in RAM.
 func SampleEnglish() []string {
-	var out []string 
-	file, err := os.Open("data/big.txt") 
-    if (err != nil) { 
-    	fmt.Println(err)
-        return out
-    }
-    reader := bufio.NewReader(file)
-    scanner := bufio.NewScanner(reader)
-	scanner.Split(bufio.ScanWords)
-	// Count the words.
-	count := 0
-	for scanner.Scan() {
-		word := strings.Trim(scanner.Text(), "=+'|_,-!;:\"?.")
-		out = append(out, strings.ToLower(word))
-		count++
+	var out []string
+	file, err := os.Open("data/big.txt")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return out
 	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintln(os.Stderr, "reading input:", err)
+	defer file.Close()
+
+	tok := NewWordTokenizer(file)
+	for {
+		word, ok := tok.Next()
+		if !ok {
+			break
+		}
+		out = append(out, strings.ToLower(word))
 	}
 
 	return out
@@ -0,0 +1,120 @@
+package fuzzy
+
+import "testing"
+
+func TestLevenshteinRuneCorrect(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"kitten", "sitting", 3},
+		{"", "", 0},
+		{"", "abc", 3},
+		{"café", "cafe", 1},
+		{"日本語", "日本", 1},
+		{"日本語", "本語", 1},
+	}
+	for _, c := range cases {
+		if got := Levenshtein(c.a, c.b); got != c.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestDamerauLevenshteinTransposition(t *testing.T) {
+	if got := DamerauLevenshtein("ab", "ba"); got != 1 {
+		t.Errorf("DamerauLevenshtein(ab, ba) = %d, want 1", got)
+	}
+	if got := Levenshtein("ab", "ba"); got != 2 {
+		t.Errorf("Levenshtein(ab, ba) = %d, want 2", got)
+	}
+	if got := DamerauLevenshtein("áb", "bá"); got != 1 {
+		t.Errorf("DamerauLevenshtein(áb, bá) = %d, want 1", got)
+	}
+}
+
+func TestEdits1Deletes(t *testing.T) {
+	model := NewModel()
+	edits := model.Edits1("日本語")
+	want := map[string]bool{"本語": true, "日語": true, "日本": true}
+	for _, e := range edits {
+		if !want[e] {
+			t.Errorf("Edits1(日本語) produced unexpected edit %q", e)
+		}
+		delete(want, e)
+	}
+	if len(want) != 0 {
+		t.Errorf("Edits1(日本語) missing edits %v", want)
+	}
+}
+
+func TestEdits1AlphabetGated(t *testing.T) {
+	model := NewModel()
+	edits := model.Edits1("ab")
+	for _, e := range edits {
+		if len(e) != 1 {
+			t.Fatalf("Edits1 without an alphabet produced a non-delete edit %q", e)
+		}
+	}
+
+	model.SetAlphabet([]rune("ab"))
+	edits = model.Edits1("ab")
+
+	found := map[string]bool{}
+	for _, e := range edits {
+		found[e] = true
+	}
+	for _, want := range []string{"a", "b", "ba", "aab", "bab", "aba", "abb"} {
+		if !found[want] {
+			t.Errorf("Edits1(ab) with alphabet [a b] missing edit %q, got %v", want, edits)
+		}
+	}
+}
+
+// TestEditsMultiIgnoresAlphabet checks that EditsMulti, which backs the
+// suggest-index built by TrainWord and the query path in Lookup, always
+// generates delete-only edits even once an alphabet has been set with
+// SetAlphabet: the symmetric-delete premise documented by Lookup only
+// holds if indexing never grows inserts/replacements/transpositions.
+func TestEditsMultiIgnoresAlphabet(t *testing.T) {
+	model := NewModel()
+	model.SetAlphabet([]rune("abcdefghijklmnopqrstuvwxyz"))
+
+	edits := model.EditsMulti("hello", 2)
+	n := len("hello")
+	for _, e := range edits {
+		if len(e) >= n {
+			t.Fatalf("EditsMulti with an alphabet set produced a non-delete edit %q", e)
+		}
+	}
+}
+
+// TestTrainWordSuggestIndexIgnoresAlphabet is the end-to-end version of
+// TestEditsMultiIgnoresAlphabet: training with an alphabet set must not
+// blow up model.suggest with alphabet-gated edits.
+func TestTrainWordSuggestIndexIgnoresAlphabet(t *testing.T) {
+	model := NewModel()
+	model.SetAlphabet([]rune("abcdefghijklmnopqrstuvwxyz"))
+	model.SetThreshold(1)
+
+	model.TrainWord("hello")
+
+	for key := range model.suggest {
+		if len(key) >= len("hello") {
+			t.Fatalf("model.suggest has non-delete key %q after training with an alphabet set", key)
+		}
+	}
+}
+
+func TestEdits1DeprecatedFreeFunction(t *testing.T) {
+	got := Edits1("abc")
+	want := new(Model).Edits1("abc")
+	if len(got) != len(want) {
+		t.Fatalf("Edits1(abc) = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("Edits1(abc) = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Verbosity controls how many results Lookup returns.
+type Verbosity int
+
+const (
+	// Top returns only the single best match.
+	Top Verbosity = iota
+	// Closest returns every match tied for the smallest edit distance found.
+	Closest
+	// All returns every match within maxEditDistance.
+	All
+)
+
+// Lookup performs a SymSpell-style symmetric delete search: it generates
+// deletes of input up to maxEditDistance and unions the dictionary terms
+// whose precomputed delete-sets (model.suggest) intersect with them. Each
+// candidate is then verified against the true Levenshtein distance to
+// input and can be tuned per call to trade recall for latency, but
+// maxEditDistance is only fully honored up to model.depth: model.suggest
+// only contains delete-variants generated at training time to that
+// depth, so a maxEditDistance greater than model.depth will silently
+// miss candidates that a retrained model with a larger depth would
+// have found.
+func (model *Model) Lookup(input string, maxEditDistance int, verbosity Verbosity) []Potential {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	input = strings.ToLower(input)
+	inputLen := len([]rune(input))
+
+	candidates := make(map[string]*Potential)
+
+	consider := func(term string) {
+		if _, ok := candidates[term]; ok {
+			return
+		}
+		if abs(inputLen-len([]rune(term))) > maxEditDistance {
+			return
+		}
+		leven := Levenshtein(input, term)
+		if leven > maxEditDistance {
+			return
+		}
+		candidates[term] = &Potential{Term: term, Score: model.score(term), Leven: leven, Method: 4}
+	}
+
+	// The input itself, if it is a known dictionary term.
+	if model.score(input) > 0 {
+		consider(input)
+	}
+
+	// Deletes of the input, from 0 (the input itself) up to maxEditDistance,
+	// checked against the delete-index built during training.
+	deletes := []string{input}
+	if maxEditDistance > 0 {
+		deletes = append(deletes, model.EditsMulti(input, maxEditDistance)...)
+	}
+	for _, del := range deletes {
+		// del may itself be a dictionary term reached by deleting from
+		// input alone, the symmetric counterpart of a term's own
+		// distance-0 delete that model.suggest never indexes.
+		if model.score(del) > 0 {
+			consider(del)
+		}
+		if terms, ok := model.suggest[del]; ok {
+			for _, term := range terms {
+				consider(term)
+			}
+		}
+	}
+
+	results := make([]Potential, 0, len(candidates))
+	for _, pot := range candidates {
+		results = append(results, *pot)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Leven != results[j].Leven {
+			return results[i].Leven < results[j].Leven
+		}
+		return results[i].Score > results[j].Score
+	})
+
+	switch verbosity {
+	case Top:
+		if len(results) > 1 {
+			results = results[:1]
+		}
+	case Closest:
+		if len(results) > 0 {
+			min := results[0].Leven
+			cut := len(results)
+			for i, pot := range results {
+				if pot.Leven != min {
+					cut = i
+					break
+				}
+			}
+			results = results[:cut]
+		}
+	case All:
+		// Already bounded to maxEditDistance above.
+	}
+
+	return results
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+package fuzzy
+
+import "testing"
+
+func TestLookupFindsExactAndNearMatches(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 5; i++ {
+		model.TrainWord("information")
+	}
+
+	results := model.Lookup("information", 0, Top)
+	if len(results) != 1 || results[0].Term != "information" || results[0].Leven != 0 {
+		t.Fatalf("Lookup(information, 0, Top) = %v, want exact match", results)
+	}
+
+	results = model.Lookup("infromation", 2, All)
+	found := false
+	for _, r := range results {
+		if r.Term == "information" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Lookup(infromation, 2, All) = %v, want to contain information", results)
+	}
+}
+
+func TestLookupHonorsVerbosity(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 5; i++ {
+		model.TrainWord("cat")
+		model.TrainWord("can")
+		model.TrainWord("car")
+	}
+
+	top := model.Lookup("ca", 1, Top)
+	if len(top) > 1 {
+		t.Fatalf("Lookup(ca, 1, Top) returned %d results, want at most 1", len(top))
+	}
+
+	closest := model.Lookup("ca", 1, Closest)
+	for _, r := range closest {
+		if r.Leven != closest[0].Leven {
+			t.Fatalf("Lookup(ca, 1, Closest) = %v, want all results tied at the smallest distance", closest)
+		}
+	}
+
+	all := model.Lookup("ca", 1, All)
+	if len(all) < len(closest) {
+		t.Fatalf("Lookup(ca, 1, All) returned fewer results (%d) than Closest (%d)", len(all), len(closest))
+	}
+}
+
+// TestLookupMultiByteLengthFilter checks that the length prefilter in
+// consider compares rune counts, not byte counts: "本語" is 2 runes/6
+// bytes and "日本語" is 3 runes/9 bytes, a byte-length gap of 3 that
+// would wrongly exceed maxEditDistance=1 even though the true edit
+// distance (and rune-length gap) is 1.
+func TestLookupMultiByteLengthFilter(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 5; i++ {
+		model.TrainWord("本語")
+	}
+
+	results := model.Lookup("日本語", 1, All)
+	found := false
+	for _, r := range results {
+		if r.Term == "本語" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Lookup(日本語, 1, All) = %v, want to contain 本語", results)
+	}
+}
+
+// TestLookupBoundedByModelDepth documents that maxEditDistance is only
+// fully honored up to model.depth: model.suggest only holds
+// delete-variants generated at training time, so a query asking for more
+// edits than depth was trained with still misses true matches beyond
+// that depth.
+func TestLookupBoundedByModelDepth(t *testing.T) {
+	model := NewModel()
+	model.SetDepth(1)
+	for i := 0; i < 5; i++ {
+		model.TrainWord("information")
+	}
+
+	results := model.Lookup("infrmatio", 3, All)
+	if len(results) != 0 {
+		t.Fatalf("Lookup(infrmatio, 3, All) with depth 1 = %v, want no results beyond the trained depth", results)
+	}
+}
@@ -0,0 +1,271 @@
+package fuzzy
+
+import (
+	"bufio"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Format selects the on-disk representation used by SaveTo/LoadFrom.
+type Format int
+
+const (
+	// FormatGob is a compact binary encoding of the whole model, fast to
+	// reload but opaque to diffing or merging by hand.
+	FormatGob Format = iota
+	// FormatJSONLines is a streaming, line-delimited JSON encoding: a
+	// header line followed by one line per trained term and one line
+	// per suggest-index entry, so models can be diffed, inspected or
+	// merged a line at a time without loading the whole file.
+	FormatJSONLines
+)
+
+// modelVersion is bumped whenever the on-disk layout changes, so Load
+// can refuse a file it would otherwise silently misinterpret.
+const modelVersion = 1
+
+// modelState mirrors Model's fields with exported names, since gob only
+// encodes exported fields.
+type modelState struct {
+	Version   int
+	Data      map[string]int
+	Suggest   map[string][]string
+	Depth     int
+	Threshold int
+	Maxcount  int
+	Alphabet  []rune
+}
+
+type jsonLine struct {
+	Type      string   `json:"type"`
+	Version   int      `json:"version,omitempty"`
+	Depth     int      `json:"depth,omitempty"`
+	Threshold int      `json:"threshold,omitempty"`
+	Maxcount  int      `json:"maxcount,omitempty"`
+	Alphabet  string   `json:"alphabet,omitempty"`
+	Term      string   `json:"term,omitempty"`
+	Count     int      `json:"count,omitempty"`
+	Key       string   `json:"key,omitempty"`
+	Terms     []string `json:"terms,omitempty"`
+}
+
+// Save writes model to path, choosing FormatJSONLines for a ".json" or
+// ".jsonl" extension and FormatGob otherwise.
+func (model *Model) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return model.SaveTo(f, formatForPath(path))
+}
+
+// Load reads a model previously written by Save into model, replacing
+// its Data, suggest index, depth, threshold, maxcount and alphabet.
+func (model *Model) Load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return model.LoadFrom(f, formatForPath(path))
+}
+
+func formatForPath(path string) Format {
+	switch filepath.Ext(path) {
+	case ".json", ".jsonl":
+		return FormatJSONLines
+	default:
+		return FormatGob
+	}
+}
+
+// SaveTo writes model to w in the given format.
+func (model *Model) SaveTo(w io.Writer, format Format) error {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+
+	switch format {
+	case FormatJSONLines:
+		return model.saveJSONLines(w)
+	default:
+		return model.saveGob(w)
+	}
+}
+
+// LoadFrom reads a model from r in the given format into model.
+func (model *Model) LoadFrom(r io.Reader, format Format) error {
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	switch format {
+	case FormatJSONLines:
+		return model.loadJSONLines(r)
+	default:
+		return model.loadGob(r)
+	}
+}
+
+func (model *Model) saveGob(w io.Writer) error {
+	state := modelState{
+		Version:   modelVersion,
+		Data:      model.Data,
+		Suggest:   model.suggest,
+		Depth:     model.depth,
+		Threshold: model.threshold,
+		Maxcount:  model.maxcount,
+		Alphabet:  model.alphabet,
+	}
+	return gob.NewEncoder(w).Encode(&state)
+}
+
+func (model *Model) loadGob(r io.Reader) error {
+	var state modelState
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return err
+	}
+	if state.Version != modelVersion {
+		return fmt.Errorf("fuzzy: unsupported model version %d (want %d)", state.Version, modelVersion)
+	}
+	model.Data = state.Data
+	model.suggest = state.Suggest
+	model.depth = state.Depth
+	model.threshold = state.Threshold
+	model.maxcount = state.Maxcount
+	model.alphabet = state.Alphabet
+	if model.Data == nil {
+		model.Data = make(map[string]int)
+	}
+	if model.suggest == nil {
+		model.suggest = make(map[string][]string)
+	}
+	return nil
+}
+
+func (model *Model) saveJSONLines(w io.Writer) error {
+	enc := json.NewEncoder(w)
+
+	header := jsonLine{
+		Type:      "header",
+		Version:   modelVersion,
+		Depth:     model.depth,
+		Threshold: model.threshold,
+		Maxcount:  model.maxcount,
+		Alphabet:  string(model.alphabet),
+	}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	for term, count := range model.Data {
+		if err := enc.Encode(jsonLine{Type: "term", Term: term, Count: count}); err != nil {
+			return err
+		}
+	}
+
+	for key, terms := range model.suggest {
+		if err := enc.Encode(jsonLine{Type: "suggest", Key: key, Terms: terms}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (model *Model) loadJSONLines(r io.Reader) error {
+	model.Data = make(map[string]int)
+	model.suggest = make(map[string][]string)
+	sawHeader := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry jsonLine
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return err
+		}
+		switch entry.Type {
+		case "header":
+			if entry.Version != modelVersion {
+				return fmt.Errorf("fuzzy: unsupported model version %d (want %d)", entry.Version, modelVersion)
+			}
+			model.depth = entry.Depth
+			model.threshold = entry.Threshold
+			model.maxcount = entry.Maxcount
+			model.alphabet = []rune(entry.Alphabet)
+			sawHeader = true
+		case "term":
+			model.Data[entry.Term] = entry.Count
+		case "suggest":
+			model.suggest[entry.Key] = entry.Terms
+		default:
+			return fmt.Errorf("fuzzy: unknown model line type %q", entry.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if !sawHeader {
+		return fmt.Errorf("fuzzy: missing model header")
+	}
+	return nil
+}
+
+// Merge combines other into model: term counts are summed, suggest
+// lists are unioned, and maxcount is recomputed, so models trained
+// independently (e.g. in a map-reduce job) can be combined. other is
+// snapshotted under its own lock before model is locked, so concurrent
+// a.Merge(b) and b.Merge(a) calls cannot deadlock on each other's lock.
+func (model *Model) Merge(other *Model) {
+	if other == model {
+		return
+	}
+
+	other.mu.RLock()
+	data := make(map[string]int, len(other.Data))
+	for term, count := range other.Data {
+		data[term] = count
+	}
+	suggest := make(map[string][]string, len(other.suggest))
+	for key, terms := range other.suggest {
+		copied := make([]string, len(terms))
+		copy(copied, terms)
+		suggest[key] = copied
+	}
+	other.mu.RUnlock()
+
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	for term, count := range data {
+		model.Data[term] += count
+		if model.Data[term] > model.maxcount {
+			model.maxcount = model.Data[term]
+		}
+	}
+
+	for key, terms := range suggest {
+		existing := model.suggest[key]
+		for _, term := range terms {
+			found := false
+			for _, have := range existing {
+				if have == term {
+					found = true
+					break
+				}
+			}
+			if !found {
+				existing = append(existing, term)
+			}
+		}
+		model.suggest[key] = existing
+	}
+}
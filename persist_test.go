@@ -0,0 +1,84 @@
+package fuzzy
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func trainedModel() *Model {
+	model := NewModel()
+	model.Train(corpus(50))
+	return model
+}
+
+func TestSaveLoadGobRoundTrip(t *testing.T) {
+	model := trainedModel()
+
+	var buf bytes.Buffer
+	if err := model.SaveTo(&buf, FormatGob); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+
+	reloaded := NewModel()
+	if err := reloaded.LoadFrom(&buf, FormatGob); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+
+	for term, count := range model.Data {
+		if reloaded.Data[term] != count {
+			t.Fatalf("Data[%q] = %d, want %d", term, reloaded.Data[term], count)
+		}
+	}
+	if reloaded.SpellCheck("wrd1") != model.SpellCheck("wrd1") {
+		t.Fatalf("SpellCheck mismatch after gob round trip")
+	}
+}
+
+func TestSaveLoadJSONLinesRoundTrip(t *testing.T) {
+	model := trainedModel()
+
+	var buf bytes.Buffer
+	if err := model.SaveTo(&buf, FormatJSONLines); err != nil {
+		t.Fatalf("SaveTo: %v", err)
+	}
+
+	reloaded := NewModel()
+	if err := reloaded.LoadFrom(&buf, FormatJSONLines); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+
+	for term, count := range model.Data {
+		if reloaded.Data[term] != count {
+			t.Fatalf("Data[%q] = %d, want %d", term, reloaded.Data[term], count)
+		}
+	}
+	if reloaded.SpellCheck("wrd1") != model.SpellCheck("wrd1") {
+		t.Fatalf("SpellCheck mismatch after json-lines round trip")
+	}
+}
+
+// TestMergeNoDeadlock reproduces a.Merge(b) and b.Merge(a) running
+// concurrently, the map-reduce combine pattern Merge is meant to
+// support, and fails if they deadlock on each other's lock.
+func TestMergeNoDeadlock(t *testing.T) {
+	a := trainedModel()
+	b := trainedModel()
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); a.Merge(b) }()
+		go func() { defer wg.Done(); b.Merge(a) }()
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Merge deadlocked")
+	}
+}
@@ -0,0 +1,83 @@
+package fuzzy
+
+import (
+	"math"
+	"strings"
+)
+
+// maxSegLen bounds how long a single segmented word can be, keeping
+// Segment's DP at O(n*maxSegLen) time instead of O(n^2).
+const maxSegLen = 20
+
+// segPoint is the best way found so far to reach a given position in
+// the input: which word ends there, where it started, and the
+// cumulative log-probability and edit-distance cost of the segmentation
+// up to that point.
+type segPoint struct {
+	prevPos  int
+	word     string
+	distSum  int
+	logProb  float64
+}
+
+// Segment splits a whitespace-free string, such as "thequickbrownfox",
+// into the most probable sequence of dictionary words. It is a
+// triangular-matrix DP: for each position it considers every window up
+// to maxSegLen characters long, spell-corrects that window with Lookup,
+// and keeps only the best-scoring predecessor for each position, so it
+// runs in O(n*maxSegLen) time and O(n) memory. It returns the
+// reconstructed segmentation, the sum of edit distances used to correct
+// each word, and the overall probability of the chosen segmentation.
+func (model *Model) Segment(input string) (segmented string, distanceSum int, probability float64) {
+	model.mu.RLock()
+	maxcount := model.maxcount
+	vocabSize := len(model.Data)
+	depth := model.depth
+	model.mu.RUnlock()
+
+	denom := float64(maxcount + vocabSize)
+	if denom <= 0 {
+		denom = 1
+	}
+
+	runes := []rune(strings.ToLower(input))
+	n := len(runes)
+	if n == 0 {
+		return "", 0, 0
+	}
+
+	best := make([]*segPoint, n+1)
+	best[0] = &segPoint{}
+
+	for i := 0; i < n; i++ {
+		if best[i] == nil {
+			continue
+		}
+		limit := min(maxSegLen, n-i)
+		for j := 1; j <= limit; j++ {
+			window := string(runes[i : i+j])
+
+			word := window
+			dist := j // no dictionary match: treat the whole window as wrong
+			freq := 0
+			if matches := model.Lookup(window, depth, Top); len(matches) > 0 {
+				word = matches[0].Term
+				dist = matches[0].Leven
+				freq = matches[0].Score
+			}
+
+			logProb := best[i].logProb + math.Log(float64(freq+1)/denom) - float64(dist)
+			pos := i + j
+			if best[pos] == nil || logProb > best[pos].logProb {
+				best[pos] = &segPoint{prevPos: i, word: word, distSum: best[i].distSum + dist, logProb: logProb}
+			}
+		}
+	}
+
+	words := []string{}
+	for pos := n; pos > 0; pos = best[pos].prevPos {
+		words = append([]string{best[pos].word}, words...)
+	}
+
+	return strings.Join(words, " "), best[n].distSum, math.Exp(best[n].logProb)
+}
@@ -0,0 +1,60 @@
+package fuzzy
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	model := NewModel()
+	words := []string{"the", "quick", "brown", "fox", "jumps", "over", "the", "lazy", "dog"}
+	for i := 0; i < 5; i++ {
+		model.Train(words)
+	}
+
+	segmented, _, probability := model.Segment("thequickbrownfox")
+	if segmented != "the quick brown fox" {
+		t.Fatalf("Segment(thequickbrownfox) = %q, want %q", segmented, "the quick brown fox")
+	}
+	if probability <= 0 {
+		t.Fatalf("Segment(thequickbrownfox) probability = %v, want > 0", probability)
+	}
+}
+
+func TestSegmentEmpty(t *testing.T) {
+	model := NewModel()
+	segmented, distance, probability := model.Segment("")
+	if segmented != "" || distance != 0 || probability != 0 {
+		t.Fatalf("Segment(\"\") = (%q, %d, %v), want (\"\", 0, 0)", segmented, distance, probability)
+	}
+}
+
+// TestSegmentConcurrentSetDepth exercises Segment against SetDepth from
+// another goroutine under -race: Segment must snapshot model.depth
+// itself rather than reading it from within its Lookup loop, or the
+// read races with a concurrent write.
+func TestSegmentConcurrentSetDepth(t *testing.T) {
+	model := NewModel()
+	words := []string{"the", "quick", "brown", "fox", "jumps", "over", "the", "lazy", "dog"}
+	for i := 0; i < 5; i++ {
+		model.Train(words)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			model.Segment("thequickbrownfox")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			model.SetDepth(2)
+		}
+	}()
+
+	wg.Wait()
+}
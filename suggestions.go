@@ -0,0 +1,106 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggestion is a single scored, ranked alternative for an input term.
+type Suggestion struct {
+	Term      string
+	Distance  int
+	Frequency int
+	Score     float64
+}
+
+// distanceWeight and affixWeight tune how much edit distance costs and
+// how much a shared prefix/suffix boosts a candidate's score.
+const (
+	distanceWeight = 1.0
+	affixWeight    = 0.25
+)
+
+// maxCount returns model.maxcount under a read lock.
+func (model *Model) maxCount() int {
+	model.mu.RLock()
+	defer model.mu.RUnlock()
+	return model.maxcount
+}
+
+// commonAffix returns the lengths of the matching prefix and (non
+// overlapping) suffix shared by a and b, walking runes in from both
+// ends. It is used to bias ranking toward candidates that look like the
+// input; candidates at the same edit distance can still tie on this
+// bonus (e.g. "access" and "across" both share 5 affix runes with
+// "acress"), in which case term frequency is what actually separates
+// them.
+func commonAffix(a, b []rune) (prefix, suffix int) {
+	n := min(len(a), len(b))
+	for prefix < n && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < n-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return
+}
+
+// score combines edit distance, normalized term frequency and a
+// common-prefix/suffix bonus into a single value, higher is better.
+func score(input, term string, distance, frequency, maxcount int) float64 {
+	prefix, suffix := commonAffix([]rune(input), []rune(term))
+	frequencyNorm := 0.0
+	if maxcount > 0 {
+		frequencyNorm = float64(frequency) / float64(maxcount)
+	}
+	return frequencyNorm - distanceWeight*float64(distance) + affixWeight*float64(prefix+suffix)
+}
+
+// SuggestionsN returns up to n scored, deduplicated alternatives for
+// input, sorted by a composite score of edit distance, term frequency
+// and shared prefix/suffix with input. A non-positive n returns every
+// candidate found.
+func (model *Model) SuggestionsN(input string, n int) []Suggestion {
+	input = strings.ToLower(input)
+	maxcount := model.maxCount()
+	potentials := model.suggestPotential(input, true)
+
+	suggestions := make([]Suggestion, 0, len(potentials))
+	for _, pot := range potentials {
+		suggestions = append(suggestions, Suggestion{
+			Term:      pot.Term,
+			Distance:  pot.Leven,
+			Frequency: pot.Score,
+			Score:     score(input, pot.Term, pot.Leven, pot.Score, maxcount),
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Score != suggestions[j].Score {
+			return suggestions[i].Score > suggestions[j].Score
+		}
+		return suggestions[i].Term < suggestions[j].Term
+	})
+
+	if n > 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// Suggestions is a backward-compatible wrapper around SuggestionsN: it
+// returns just the term for each ranked suggestion. Non-exhaustive calls
+// are capped at the 10 best matches; exhaustive calls return every
+// candidate found.
+func (model *Model) Suggestions(input string, exhaustive bool) []string {
+	n := 10
+	if exhaustive {
+		n = -1
+	}
+	ranked := model.SuggestionsN(input, n)
+	output := make([]string, len(ranked))
+	for i, suggestion := range ranked {
+		output[i] = suggestion.Term
+	}
+	return output
+}
@@ -0,0 +1,63 @@
+package fuzzy
+
+import "testing"
+
+// TestSuggestionsNRanksAcressToAccess trains "access" and "across" to
+// the same edit distance and affix-bonus from "acress" (both share 5
+// runes of prefix+suffix with it, per commonAffix), so frequency is what
+// must break the tie: "access" is trained more often and should sort
+// first.
+func TestSuggestionsNRanksAcressToAccess(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 10; i++ {
+		model.TrainWord("access")
+	}
+	for i := 0; i < 4; i++ {
+		model.TrainWord("across")
+	}
+
+	ranked := model.SuggestionsN("acress", 2)
+	if len(ranked) == 0 || ranked[0].Term != "access" {
+		t.Fatalf("SuggestionsN(acress) top = %v, want access first", ranked)
+	}
+}
+
+func TestSuggestionsNDedupesAndCapsN(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 5; i++ {
+		model.TrainWord("test")
+		model.TrainWord("text")
+		model.TrainWord("tent")
+	}
+
+	ranked := model.SuggestionsN("tes", 2)
+	if len(ranked) > 2 {
+		t.Fatalf("SuggestionsN(tes, 2) returned %d results, want at most 2", len(ranked))
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range ranked {
+		if seen[s.Term] {
+			t.Fatalf("SuggestionsN(tes, 2) returned duplicate term %q", s.Term)
+		}
+		seen[s.Term] = true
+	}
+}
+
+func TestSuggestionsBackwardCompatible(t *testing.T) {
+	model := NewModel()
+	for i := 0; i < 5; i++ {
+		model.TrainWord("hello")
+	}
+
+	terms := model.Suggestions("hallo", false)
+	found := false
+	for _, term := range terms {
+		if term == "hello" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Suggestions(hallo) = %v, want to contain hello", terms)
+	}
+}
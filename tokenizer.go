@@ -0,0 +1,115 @@
+package fuzzy
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Tokenizer yields successive tokens from a stream. Next returns false
+// once the stream is exhausted. Implementations that wrap a scanner can
+// additionally implement Err() error to surface read failures to
+// TrainReader.
+type Tokenizer interface {
+	Next() (string, bool)
+}
+
+type whitespaceTokenizer struct {
+	scanner *bufio.Scanner
+}
+
+// NewWhitespaceTokenizer splits r on runs of whitespace, the same
+// behaviour the original SampleEnglish used internally.
+func NewWhitespaceTokenizer(r io.Reader) Tokenizer {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return &whitespaceTokenizer{scanner: scanner}
+}
+
+func (t *whitespaceTokenizer) Next() (string, bool) {
+	if t.scanner.Scan() {
+		return t.scanner.Text(), true
+	}
+	return "", false
+}
+
+func (t *whitespaceTokenizer) Err() error {
+	return t.scanner.Err()
+}
+
+var wordPattern = regexp.MustCompile(`\p{L}+`)
+
+type wordTokenizer struct {
+	scanner *bufio.Scanner
+}
+
+// NewWordTokenizer splits r into runs of unicode letters (\p{L}+),
+// discarding punctuation and digits, so a corpus doesn't need
+// pre-cleaning before training.
+func NewWordTokenizer(r io.Reader) Tokenizer {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(wordSplit)
+	return &wordTokenizer{scanner: scanner}
+}
+
+func (t *wordTokenizer) Next() (string, bool) {
+	if t.scanner.Scan() {
+		return t.scanner.Text(), true
+	}
+	return "", false
+}
+
+func (t *wordTokenizer) Err() error {
+	return t.scanner.Err()
+}
+
+func wordSplit(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	loc := wordPattern.FindIndex(data)
+	if loc == nil {
+		if atEOF {
+			return len(data), nil, nil
+		}
+		return 0, nil, nil
+	}
+	// The match may be truncated by the end of the buffer; ask for more
+	// data before accepting it, unless there is no more to read.
+	if loc[1] == len(data) && !atEOF {
+		return 0, nil, nil
+	}
+	return loc[1], data[loc[0]:loc[1]], nil
+}
+
+type ngramTokenizer struct {
+	words Tokenizer
+	n     int
+	buf   []string
+	done  bool
+}
+
+// NewNGramTokenizer yields overlapping runs of n whitespace-delimited
+// words from r, e.g. with n=2 "the quick brown" tokenizes to "the
+// quick" then "quick brown".
+func NewNGramTokenizer(r io.Reader, n int) Tokenizer {
+	if n < 1 {
+		n = 1
+	}
+	return &ngramTokenizer{words: NewWhitespaceTokenizer(r), n: n}
+}
+
+func (t *ngramTokenizer) Next() (string, bool) {
+	if t.done {
+		return "", false
+	}
+	for len(t.buf) < t.n {
+		word, ok := t.words.Next()
+		if !ok {
+			t.done = true
+			return "", false
+		}
+		t.buf = append(t.buf, word)
+	}
+	gram := strings.Join(t.buf, " ")
+	t.buf = t.buf[1:]
+	return gram, true
+}